@@ -6,13 +6,11 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
-	"strings"
-
-	"github.com/georgysavva/scany/v2/sqlscan"
 )
 
 var (
 	errorType = reflect.TypeOf((*error)(nil)).Elem()
+	ctxType   = reflect.TypeOf((*context.Context)(nil)).Elem()
 )
 
 type QueryLoader interface {
@@ -50,11 +48,66 @@ func getErrorValue(err error) reflect.Value {
 	}
 }
 
+// BindOptions controls how Bind rewrites and executes the queries declared
+// on a repository struct.
+type BindOptions struct {
+	// Dialect determines how named markers (":name", "@name") and bare "?"
+	// placeholders are rewritten at bind time. Defaults to DialectSQLite,
+	// which leaves "?" untouched.
+	Dialect Dialect
+
+	// LastInsertIDSupport must be false for database systems (PostgreSQL)
+	// that do not support the concept of last insert id.
+	LastInsertIDSupport bool
+
+	// Middleware wraps every Exec*/Query* invocation, outermost first. See
+	// QueryMiddleware.
+	Middleware []QueryMiddleware
+
+	// Prepare, when true, pre-prepares every tagged query against handle
+	// (which must be a *sql.DB) at bind time instead of re-parsing it on
+	// every call. handle must be a *sql.DB when Prepare is set. Call Close
+	// on dst once it's no longer needed to release the cached statements.
+	Prepare bool
+}
+
 // Bind binds all the functions declared in the given struct.
 //
 // Some database systems (PostgreSQL) do not support the concept of last insert id. For such databases, the
 // lastInsertIDSupport parameter must be false.
 func Bind(ctx context.Context, handle DBResource, dst any, qLoder QueryLoader, lastInsertIDSupport bool) error {
+	return BindWithOptions(ctx, handle, dst, qLoder, BindOptions{
+		Dialect:             DialectSQLite,
+		LastInsertIDSupport: lastInsertIDSupport,
+	})
+}
+
+// BindWithOptions is the dialect-aware counterpart of Bind. It additionally
+// rewrites named parameters (":name", "@name") and bare "?" placeholders in
+// each tagged query into the form opts.Dialect expects, and lets a
+// repository function take a single struct argument whose `db:"..."` tagged
+// fields are matched to those named markers by name.
+//
+// A tagged function may also declare a leading context.Context parameter
+// (overriding ctx for that call) and/or a leading DBResource parameter
+// (overriding handle, typically with a *sql.Tx), in that order, e.g.
+// func(ctx context.Context, id int64) (User, error) or
+// func(ctx context.Context, tx DBResource, id int64) (User, error). T in
+// such a signature is resolved like any other reflect-typed field: it works
+// unchanged on a generic repository struct (type Repo[T any] struct{ ... }),
+// since the field's type is already concrete once T is instantiated.
+//
+// A trailing variadic parameter, e.g. func(ctx context.Context, args ...any)
+// ([]T, error), is also supported: each argument passed at the call site
+// becomes its own positional query argument, the same as passing them
+// individually to a fixed-arity field.
+//
+// A field declared with no error return (e.g. func() []T or
+// func(string, int) (int64, int64)) panics on error instead of returning
+// one, since its signature has no slot for it. These shapes are kept for
+// backward compatibility; prefer adding an error return (or QueryOne/
+// QueryAll/Exec) in new code.
+func BindWithOptions(ctx context.Context, handle DBResource, dst any, qLoder QueryLoader, opts BindOptions) error {
 	vType := reflect.TypeOf(dst)
 
 	if vType.Kind() != reflect.Pointer {
@@ -65,210 +118,374 @@ func Bind(ctx context.Context, handle DBResource, dst any, qLoder QueryLoader, l
 		return fmt.Errorf("must be a pointer to a struct. found: %v", vType)
 	}
 
-	runner := func(typ reflect.Type, isExec, singleSelection bool, query string) func([]reflect.Value) []reflect.Value {
-		numOut := typ.NumOut()
-		if isExec {
-			exec := func(args []reflect.Value) (int64, int64, error) {
-				values := []any{}
+	specs, err := compileFields(ctx, handle, vType.Elem(), qLoder, opts)
+	if err != nil {
+		return err
+	}
+	if err := buildFuncs(ctx, handle, reflect.ValueOf(dst).Elem(), opts, specs); err != nil {
+		return err
+	}
+
+	if stmts := stmtsOf(specs); len(stmts) > 0 {
+		preparedStmts.Store(dst, stmts)
+	}
+	return nil
+}
+
+// fieldSpec is the result of parsing and dialect-rebinding one @exec/@query/
+// @queryOne-tagged field, independent of which DBResource it will eventually
+// run against. compileFields produces these once; buildFuncs may consume the
+// same []fieldSpec more than once (see Repository[T].WithTx) to rebuild the
+// reflect closures against a different handle without redoing any of that
+// parsing or (if opts.Prepare was set) re-preparing the statement.
+type fieldSpec struct {
+	fieldName       string
+	fieldType       reflect.Type
+	isExec          bool
+	singleSelection bool
+	script          string
+	paramNames      []string
+	stmt            *sql.Stmt
+}
+
+// compileFields resolves every @exec/@query/@queryOne-tagged func field on
+// t: loading its query through qLoder's "file:" convention, rebinding it for
+// opts.Dialect, and (if opts.Prepare is set) preparing it against handle,
+// which must then be a *sql.DB.
+func compileFields(ctx context.Context, handle DBResource, t reflect.Type, qLoder QueryLoader, opts BindOptions) ([]fieldSpec, error) {
+	var db *sql.DB
+	if opts.Prepare {
+		var ok bool
+		if db, ok = handle.(*sql.DB); !ok {
+			return nil, fmt.Errorf("bsa: BindOptions.Prepare requires handle to be a *sql.DB")
+		}
+	}
+
+	resolveQuery := func(q, fnName string) (string, []string, error) {
+		spec, err := loadQuerySpec(qLoder, q)
+		if err != nil {
+			return "", nil, errors.Join(fmt.Errorf("%s: failed to load file %s", fnName, q), err)
+		}
+		script, paramNames := rebindQuery(opts.Dialect, spec)
+		return script, paramNames, nil
+	}
+
+	prepare := func(fnName, script string) (*sql.Stmt, error) {
+		if db == nil {
+			return nil, nil
+		}
+		stmt, err := db.PrepareContext(ctx, script)
+		if err != nil {
+			return nil, errors.Join(fmt.Errorf("%s: failed to prepare query", fnName), err)
+		}
+		return stmt, nil
+	}
+
+	var specs []fieldSpec
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Type.Kind() != reflect.Func || !field.IsExported() {
+			continue
+		}
+
+		exec := field.Tag.Get("@exec")
+		queryAll := field.Tag.Get("@query")
+		queryOne := field.Tag.Get("@queryOne")
+
+		var (
+			q               string
+			isExec          bool
+			singleSelection bool
+		)
+		switch {
+		case queryOne != "":
+			q, singleSelection = queryOne, true
+		case queryAll != "":
+			q = queryAll
+		case exec != "":
+			q, isExec = exec, true
+		default:
+			return nil, fmt.Errorf("%s: function is defined but does not have any queries", field.Name)
+		}
+
+		script, paramNames, err := resolveQuery(q, field.Name)
+		if err != nil {
+			return nil, err
+		}
+		stmt, err := prepare(field.Name, script)
+		if err != nil {
+			return nil, err
+		}
+
+		specs = append(specs, fieldSpec{
+			fieldName:       field.Name,
+			fieldType:       field.Type,
+			isExec:          isExec,
+			singleSelection: singleSelection,
+			script:          script,
+			paramNames:      paramNames,
+			stmt:            stmt,
+		})
+	}
+	return specs, nil
+}
+
+// buildFuncs assembles the reflect.MakeFunc closures described by specs and
+// assigns each to its field on value, dispatching against handle by default.
+func buildFuncs(ctx context.Context, handle DBResource, value reflect.Value, opts BindOptions, specs []fieldSpec) error {
+	for _, spec := range specs {
+		fn := runner(ctx, handle, opts, spec.fieldType, spec.isExec, spec.singleSelection, spec.script, spec.paramNames, spec.stmt)
+		value.FieldByName(spec.fieldName).Set(reflect.MakeFunc(spec.fieldType, fn))
+	}
+	return nil
+}
+
+// stmtsOf collects the prepared statements (if any) held by specs, in the
+// form Close expects.
+func stmtsOf(specs []fieldSpec) []*sql.Stmt {
+	var stmts []*sql.Stmt
+	for _, spec := range specs {
+		if spec.stmt != nil {
+			stmts = append(stmts, spec.stmt)
+		}
+	}
+	return stmts
+}
+
+// resolveArgs peels a leading context.Context and/or DBResource off args (in
+// that order), defaulting to bindCtx/handle when neither is present, then
+// resolves what's left into either positional values or (when paramNames is
+// non-empty and exactly one struct arg remains) the struct's tagged field
+// values in paramNames order.
+//
+// When variadic is set (the field was declared with a trailing "args
+// ...any" parameter), reflect.MakeFunc hands that parameter to us as a
+// single reflect.Value wrapping a []any, regardless of how many arguments
+// the caller actually passed -- so it's unwrapped into individual values
+// here rather than passed through as one []interface{} argument, which
+// database/sql has no driver.Valuer conversion for.
+func resolveArgs(bindCtx context.Context, handle DBResource, args []reflect.Value, paramNames []string, variadic bool) (context.Context, DBResource, []any, error) {
+	callCtx := bindCtx
+	executor := handle
+	rest := args
+
+	if len(rest) >= 1 && rest[0].Type() == ctxType {
+		callCtx = rest[0].Interface().(context.Context)
+		rest = rest[1:]
+	}
+	if len(rest) >= 1 {
+		if tx, ok := rest[0].Interface().(DBResource); ok {
+			executor = tx
+			rest = rest[1:]
+		}
+	}
+
+	if variadic && len(rest) >= 1 {
+		variadicArgs := rest[len(rest)-1]
+		rest = rest[:len(rest)-1]
+
+		values := make([]any, 0, len(rest)+variadicArgs.Len())
+		for _, v := range rest {
+			values = append(values, v.Interface())
+		}
+		for i := 0; i < variadicArgs.Len(); i++ {
+			values = append(values, variadicArgs.Index(i).Interface())
+		}
+		return callCtx, executor, values, nil
+	}
+
+	if len(paramNames) > 0 && len(rest) == 1 && rest[0].Kind() == reflect.Struct {
+		values, err := structFieldValues(rest[0], paramNames)
+		return callCtx, executor, values, err
+	}
+	values := make([]any, 0, len(rest))
+	for _, v := range rest {
+		values = append(values, v.Interface())
+	}
+	return callCtx, executor, values, nil
+}
+
+// runner builds the reflect.MakeFunc body for one tagged field, dispatching
+// against handle (and bindCtx) unless a call supplies its own leading
+// context.Context/DBResource (see resolveArgs).
+func runner(bindCtx context.Context, handle DBResource, opts BindOptions, typ reflect.Type, isExec, singleSelection bool, query string, paramNames []string, stmt *sql.Stmt) func([]reflect.Value) []reflect.Value {
+	numOut := typ.NumOut()
+	if isExec {
+		exec := func(args []reflect.Value) (int64, int64, error) {
+			ctx, executor, values, err := resolveArgs(bindCtx, handle, args, paramNames, typ.IsVariadic())
+			if err != nil {
+				return 0, 0, err
+			}
+			finalQuery, values, err := expandSliceArgs(opts.Dialect, query, values)
+			if err != nil {
+				return 0, 0, err
+			}
+
+			terminal := func(ctx context.Context, _ QueryKind, q string, a []any) (QueryResult, error) {
 				var (
-					id, affected int64
-					executor     DBResource = handle
+					result sql.Result
+					err    error
 				)
-				if len(args) >= 1 {
-					if tx, ok := args[0].Interface().(DBResource); ok {
-						executor = tx
-						for _, v := range args[1:] {
-							values = append(values, v.Interface())
-						}
-					} else {
-						for _, v := range args {
-							values = append(values, v.Interface())
-						}
-					}
+				if stmt != nil && q == query {
+					result, err = statementFor(ctx, stmt, executor).ExecContext(ctx, a...)
 				} else {
-					for _, v := range args {
-						values = append(values, v.Interface())
-					}
+					result, err = executor.ExecContext(ctx, q, a...)
 				}
-				result, err := executor.ExecContext(ctx, query, values...)
 				if err != nil {
-					return 0, 0, err
+					return QueryResult{}, err
 				}
-				if lastInsertIDSupport {
-					if id, err = result.LastInsertId(); err != nil {
-						return 0, 0, err
+				var qr QueryResult
+				if opts.LastInsertIDSupport {
+					if qr.LastInsertID, err = result.LastInsertId(); err != nil {
+						return QueryResult{}, err
 					}
 				}
-				if affected, err = result.RowsAffected(); err != nil {
-					return 0, 0, err
+				if qr.RowsAffected, err = result.RowsAffected(); err != nil {
+					return QueryResult{}, err
 				}
-				return id, affected, nil
+				return qr, nil
 			}
-			switch numOut {
-			case 0: // nothing (will panic on error)
-				return func(args []reflect.Value) (results []reflect.Value) {
-					_, _, err := exec(args)
-					if err != nil {
-						panic(err)
-					}
-					return results
-				}
-			case 1: // error only
-				return func(args []reflect.Value) (results []reflect.Value) {
-					_, _, err := exec(args)
-					results = append(results, getErrorValue(err))
-					return
-				}
-			case 2: // int64,int64 will panic on error
-				return func(args []reflect.Value) (results []reflect.Value) {
-					id, affected, err := exec(args)
-					if err != nil {
-						panic(err)
-					}
-					results = append(results, reflect.ValueOf(id), reflect.ValueOf(affected))
-					return
+
+			qr, err := chainMiddleware(opts.Middleware, terminal)(ctx, KindExec, finalQuery, values)
+			if err != nil {
+				return 0, 0, err
+			}
+			return qr.LastInsertID, qr.RowsAffected, nil
+		}
+		switch numOut {
+		// case 0 and case 2 panic on error: neither signature has a return
+		// slot to carry one, so there's no non-panicking way to honor them.
+		// They're kept for backward compatibility with existing repository
+		// fields declared this way; prefer a 1- or 3-return signature (or
+		// the typed Exec) in new code to get an error back instead.
+		case 0: // nothing (will panic on error)
+			return func(args []reflect.Value) (results []reflect.Value) {
+				_, _, err := exec(args)
+				if err != nil {
+					panic(err)
 				}
-			case 3: // int64,int64,error no panic
-				return func(args []reflect.Value) (results []reflect.Value) {
-					id, affected, err := exec(args)
-					results = append(results, reflect.ValueOf(id), reflect.ValueOf(affected), getErrorValue(err))
-					return
+				return results
+			}
+		case 1: // error only
+			return func(args []reflect.Value) (results []reflect.Value) {
+				_, _, err := exec(args)
+				results = append(results, getErrorValue(err))
+				return
+			}
+		case 2: // int64,int64 will panic on error
+			return func(args []reflect.Value) (results []reflect.Value) {
+				id, affected, err := exec(args)
+				if err != nil {
+					panic(err)
 				}
+				results = append(results, reflect.ValueOf(id), reflect.ValueOf(affected))
+				return
 			}
-		} else {
-			// select
-			Query := func(args []reflect.Value, one bool, oType reflect.Type) (any, error) {
-				values := []any{}
-				executor := handle
-
-				if len(args) >= 1 {
-					if tx, ok := args[0].Interface().(DBResource); ok {
-						executor = tx
-						for _, v := range args[1:] {
-							values = append(values, v.Interface())
-						}
-					} else {
-						for _, v := range args {
-							values = append(values, v.Interface())
-						}
-					}
+		case 3: // int64,int64,error no panic
+			return func(args []reflect.Value) (results []reflect.Value) {
+				id, affected, err := exec(args)
+				results = append(results, reflect.ValueOf(id), reflect.ValueOf(affected), getErrorValue(err))
+				return
+			}
+		}
+	} else {
+		// select
+		Query := func(args []reflect.Value, one bool, oType reflect.Type) (any, error) {
+			ctx, executor, values, err := resolveArgs(bindCtx, handle, args, paramNames, typ.IsVariadic())
+			if err != nil {
+				return nil, err
+			}
+			finalQuery, values, err := expandSliceArgs(opts.Dialect, query, values)
+			if err != nil {
+				return nil, err
+			}
+
+			kind := KindQueryAll
+			if one {
+				kind = KindQueryOne
+			}
+
+			terminal := func(ctx context.Context, _ QueryKind, q string, a []any) (QueryResult, error) {
+				var (
+					rows *sql.Rows
+					err  error
+				)
+				if stmt != nil && q == query {
+					rows, err = statementFor(ctx, stmt, executor).QueryContext(ctx, a...)
 				} else {
-					for _, v := range args {
-						values = append(values, v.Interface())
-					}
+					rows, err = executor.QueryContext(ctx, q, a...)
 				}
-				rows, err := executor.QueryContext(ctx, query, values...)
 				if err != nil {
-					return nil, err
-				}
-				addressOf := func(v reflect.Value) reflect.Value {
-					ptr := reflect.New(oType)
-					ptr.Elem().Set(v)
-					return ptr
+					return QueryResult{}, err
 				}
+				defer rows.Close()
+
 				if one {
 					if oType.Kind() == reflect.Pointer {
 						elem := oType.Elem()
-						ptr := reflect.New(elem)
-						err = sqlscan.ScanOne(ptr.Interface(), rows)
-						if err != nil && errors.Is(err, sql.ErrNoRows) {
-							return reflect.Zero(oType).Interface(), nil
+						v, err := scanOneInto(rows, elem)
+						if err != nil {
+							if errors.Is(err, sql.ErrNoRows) {
+								return QueryResult{Rows: reflect.Zero(oType).Interface()}, nil
+							}
+							return QueryResult{}, err
 						}
-						return ptr.Interface(), err
-					} else {
-						dstRefValue := reflect.New(oType)
-						err = sqlscan.ScanRow(dstRefValue.Interface(), rows)
-						if err != nil && errors.Is(err, sql.ErrNoRows) {
-							err = nil
-						}
-						return dstRefValue.Interface(), err
+						ptr := reflect.New(elem)
+						ptr.Elem().Set(v)
+						return QueryResult{Rows: ptr.Interface()}, nil
 					}
-				} else {
-					valueInstance := reflect.MakeSlice(oType, 0, 0)
-					valPointer := addressOf(valueInstance)
-					err = sqlscan.ScanAll(valPointer.Interface(), rows)
-					return valPointer.Elem().Interface(), err
-				}
-			}
-			switch numOut {
-			case 1: // type only, will panic
-				return func(args []reflect.Value) []reflect.Value {
-					rows, err := Query(args, singleSelection, typ.Out(0))
+					v, err := scanOneInto(rows, oType)
 					if err != nil {
-						panic(err)
+						if errors.Is(err, sql.ErrNoRows) {
+							return QueryResult{Rows: reflect.Zero(oType).Interface()}, nil
+						}
+						return QueryResult{}, err
 					}
-					return []reflect.Value{reflect.ValueOf(rows)}
+					return QueryResult{Rows: v.Interface()}, nil
 				}
-			case 2: // type, error no panic
-				return func(args []reflect.Value) []reflect.Value {
-					rows, err := Query(args, singleSelection, typ.Out(0))
-
-					var rowsValue reflect.Value
-					if rows == nil {
-						rowsValue = reflect.Zero(typ.Out(0))
-					} else {
-						rowsValue = reflect.ValueOf(rows)
-					}
 
-					return []reflect.Value{rowsValue, getErrorValue(err)}
+				elemType := oType.Elem()
+				pointerElems := elemType.Kind() == reflect.Pointer
+				if pointerElems {
+					elemType = elemType.Elem()
+				}
+				slice, err := scanAllInto(rows, elemType, pointerElems)
+				if err != nil {
+					return QueryResult{}, err
 				}
+				return QueryResult{Rows: slice.Interface()}, nil
 			}
-		}
-		panic(fmt.Errorf("%v: unsupported return type", typ))
-	}
-
-	vType = vType.Elem()
-	value := reflect.ValueOf(dst).Elem()
 
-	resolveQuery := func(q, fnName string) (string, error) {
-		spec, found := strings.CutPrefix(q, "file:")
-		if found {
-			if !strings.HasSuffix(spec, ".sql") {
-				spec = spec + ".sql"
-			}
-			spec, err := qLoder.Get(spec)
-			if err != nil {
-				return "", errors.Join(fmt.Errorf("%s: failed to load file %s", fnName, q), err)
-			}
-			return spec, nil
-		} else {
-			return spec, nil
+			result, err := chainMiddleware(opts.Middleware, terminal)(ctx, kind, finalQuery, values)
+			return result.Rows, err
 		}
-	}
-
-	for i := 0; i < vType.NumField(); i++ {
-		field := vType.Field(i)
-		fType := field.Type
-		if fType.Kind() == reflect.Func && field.IsExported() {
-			exec := field.Tag.Get("@exec")
-			queryAll := field.Tag.Get("@query")
-			queryOne := field.Tag.Get("@queryOne")
-
-			if queryOne != "" {
-				script, err := resolveQuery(queryOne, field.Name)
+		switch numOut {
+		// case 1 panics on error for the same reason exec's case 0/2 do: a
+		// single T-only return has no slot for it. Kept for backward
+		// compatibility; prefer a 2-return signature (or the typed
+		// QueryOne/QueryAll) in new code to get an error back instead.
+		case 1: // type only, will panic
+			return func(args []reflect.Value) []reflect.Value {
+				rows, err := Query(args, singleSelection, typ.Out(0))
 				if err != nil {
-					return err
+					panic(err)
 				}
-				value.FieldByName(field.Name).
-					Set(reflect.MakeFunc(fType, runner(fType, false, true, script)))
-			} else if queryAll != "" {
-				script, err := resolveQuery(queryAll, field.Name)
-				if err != nil {
-					return err
-				}
-				value.FieldByName(field.Name).
-					Set(reflect.MakeFunc(fType, runner(fType, false, false, script)))
-			} else if exec != "" {
-				script, err := resolveQuery(exec, field.Name)
-				if err != nil {
-					return err
+				return []reflect.Value{reflect.ValueOf(rows)}
+			}
+		case 2: // type, error no panic
+			return func(args []reflect.Value) []reflect.Value {
+				rows, err := Query(args, singleSelection, typ.Out(0))
+
+				var rowsValue reflect.Value
+				if rows == nil {
+					rowsValue = reflect.Zero(typ.Out(0))
+				} else {
+					rowsValue = reflect.ValueOf(rows)
 				}
-				value.FieldByName(field.Name).
-					Set(reflect.MakeFunc(fType, runner(fType, true, false, script)))
-			} else {
-				return fmt.Errorf("%s: function is defined but does not have any queries", field.Name)
+
+				return []reflect.Value{rowsValue, getErrorValue(err)}
 			}
 		}
 	}
-	return nil
+	panic(fmt.Errorf("%v: unsupported return type", typ))
 }
@@ -50,6 +50,8 @@ type UserRepository struct {
 	InsertUserQueryLoader func() (int64, int64)                                                                              `@exec:"file:insert_user_steve"`
 	AddUserTxWithError    func(bsa.DBResource, string, int) error                                                            `@exec:"insert into users(name, age) values (?, NULL)"`
 	AddUserTx             func(res bsa.DBResource, name string, age int) (lastInsertID int64, rowsAffected int64, err error) `@exec:"insert into users(name, age) values (?, ?)"`
+	SelectUserCtx         func(ctx context.Context, id int64) (User, error)                                                  `@queryOne:"select * from users where id = $1"`
+	SelectUsersByIDs      func(ctx context.Context, args ...any) ([]*User, error)                                            `@query:"select * from users where id in (?, ?)"`
 }
 
 func TestQueryOne(t *testing.T) {
@@ -83,6 +85,82 @@ func TestQueryOne(t *testing.T) {
 	}
 }
 
+// TestQueryOneWithContext exercises a field declared with a leading
+// context.Context parameter and a non-pointer return type (the shape a
+// generic repository's T-typed fields take), using a per-call context
+// distinct from the one Bind was given.
+func TestQueryOneWithContext(t *testing.T) {
+	var (
+		userRepo     = UserRepository{}
+		lastInsertID = int64(0)
+		user         User
+	)
+
+	db, err := createInMemoryDb()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	if err = bsa.Bind(context.Background(), db, &userRepo, diskFileQueryLoader(), true); err != nil {
+		t.Fatal(err)
+	}
+
+	if lastInsertID, _, err = userRepo.AddUser("jane", 30); err != nil {
+		t.Fatal(err)
+	}
+
+	callCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if user, err = userRepo.SelectUserCtx(callCtx, lastInsertID); err != nil {
+		t.Fatal(err)
+	}
+
+	if user.Name != "jane" {
+		t.Fatalf("Names don't match")
+	}
+}
+
+// TestQueryWithVariadicArgs exercises a field declared as
+// func(ctx context.Context, args ...any) ([]T, error): each argument passed
+// at the call site must reach the driver as its own positional value, not
+// as a single unsupported []interface{} argument.
+func TestQueryWithVariadicArgs(t *testing.T) {
+	var (
+		userRepo = UserRepository{}
+		idJohn   int64
+		idJane   int64
+	)
+
+	db, err := createInMemoryDb()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	if err = bsa.Bind(context.Background(), db, &userRepo, diskFileQueryLoader(), true); err != nil {
+		t.Fatal(err)
+	}
+
+	if idJohn, _, err = userRepo.AddUser("john", 65); err != nil {
+		t.Fatal(err)
+	}
+	if idJane, _, err = userRepo.AddUser("jane", 30); err != nil {
+		t.Fatal(err)
+	}
+
+	users, err := userRepo.SelectUsersByIDs(context.Background(), idJohn, idJane)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 {
+		t.Fatalf("expected 2 users, got %d", len(users))
+	}
+}
+
 func TestQuery(t *testing.T) {
 	var (
 		userRepo = UserRepository{}
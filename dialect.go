@@ -0,0 +1,112 @@
+package bsa
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Dialect identifies the target SQL database so that Bind can rewrite the
+// generic placeholder syntax used in tagged queries into whatever the
+// driver actually expects.
+type Dialect int
+
+const (
+	// DialectSQLite is the zero value, matching BindOptions.Dialect's
+	// documented default of leaving "?" untouched.
+	DialectSQLite Dialect = iota
+	DialectPostgres
+	DialectMySQL
+	DialectMSSQL
+	DialectOracle
+)
+
+// namedMarkerPattern matches a ":name"/"@name" marker, requiring a
+// non-"@"/":"/word preceding character so it doesn't mistake the second
+// colon of a Postgres "::type" cast for a named marker.
+var namedMarkerPattern = regexp.MustCompile(`(^|[^:@\w])[:@]([a-zA-Z_][a-zA-Z0-9_]*)`)
+
+// placeholderFor returns the nth (1-based) placeholder for the dialect.
+func placeholderFor(d Dialect, n int) string {
+	switch d {
+	case DialectPostgres:
+		return "$" + strconv.Itoa(n)
+	case DialectMSSQL:
+		return "@p" + strconv.Itoa(n)
+	case DialectOracle:
+		return ":v" + strconv.Itoa(n)
+	default: // DialectMySQL, DialectSQLite
+		return "?"
+	}
+}
+
+// rebindQuery rewrites a query written with the generic named markers
+// (":name", "@name") or bare "?" positional placeholders into the syntax the
+// given dialect expects. Queries that use neither are returned unchanged,
+// so hand-written dialect-specific queries keep working without
+// modification. When named markers are found, it also returns them in the
+// order they occur so the caller can resolve them against a struct's
+// `db:"..."` tags.
+func rebindQuery(d Dialect, query string) (string, []string) {
+	if namedMarkerPattern.MatchString(query) {
+		var names []string
+		n := 0
+		rewritten := namedMarkerPattern.ReplaceAllStringFunc(query, func(m string) string {
+			sub := namedMarkerPattern.FindStringSubmatch(m)
+			n++
+			names = append(names, sub[2])
+			return sub[1] + placeholderFor(d, n)
+		})
+		return rewritten, names
+	}
+
+	if !strings.ContainsRune(query, '?') {
+		return query, nil
+	}
+
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString(placeholderFor(d, n))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String(), nil
+}
+
+// structFieldValues resolves names (as produced by rebindQuery) against v's
+// `db:"..."` tagged fields, returning the values in the same order the
+// names were requested. Fields without a `db` tag are matched by their
+// lowercased field name, the same convention scannerFor's untagged columns
+// use, since named markers are written lowercase ("select ... where id =
+// :id", not ":ID").
+func structFieldValues(v reflect.Value, names []string) ([]any, error) {
+	t := v.Type()
+	byTag := make(map[string]int, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag := f.Tag.Get("db")
+		if tag == "" {
+			tag = strings.ToLower(f.Name)
+		}
+		byTag[tag] = i
+	}
+
+	values := make([]any, 0, len(names))
+	for _, name := range names {
+		idx, ok := byTag[name]
+		if !ok {
+			return nil, fmt.Errorf("bsa: no field tagged db:%q found on %s", name, t.Name())
+		}
+		values = append(values, v.Field(idx).Interface())
+	}
+	return values, nil
+}
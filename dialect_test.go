@@ -0,0 +1,52 @@
+package bsa
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRebindQueryNamedMarkers(t *testing.T) {
+	query, names := rebindQuery(DialectPostgres, "select * from users where id = :id and name = :name")
+	if query != "select * from users where id = $1 and name = $2" {
+		t.Fatalf("unexpected query: %s", query)
+	}
+	if len(names) != 2 || names[0] != "id" || names[1] != "name" {
+		t.Fatalf("unexpected names: %v", names)
+	}
+}
+
+func TestRebindQueryPreservesCasts(t *testing.T) {
+	query, names := rebindQuery(DialectPostgres, "select id::text from users where id = :id")
+	if query != "select id::text from users where id = $1" {
+		t.Fatalf("cast was mangled: %s", query)
+	}
+	if len(names) != 1 || names[0] != "id" {
+		t.Fatalf("unexpected names: %v", names)
+	}
+}
+
+func TestStructFieldValuesFallsBackToLowercasedFieldName(t *testing.T) {
+	type Params struct {
+		Name string
+		Age  int `db:"age"`
+	}
+
+	values, err := structFieldValues(reflect.ValueOf(Params{Name: "john", Age: 65}), []string{"name", "age"})
+	if err != nil {
+		t.Fatalf("structFieldValues: %v", err)
+	}
+	if values[0] != "john" || values[1] != 65 {
+		t.Fatalf("unexpected values: %v", values)
+	}
+}
+
+func TestDialectZeroValueIsSQLite(t *testing.T) {
+	var opts BindOptions
+	if opts.Dialect != DialectSQLite {
+		t.Fatalf("zero-value BindOptions.Dialect = %v, want DialectSQLite", opts.Dialect)
+	}
+	query, _ := rebindQuery(opts.Dialect, "select * from users where id = ?")
+	if query != "select * from users where id = ?" {
+		t.Fatalf("zero-value dialect rewrote a bare '?': %s", query)
+	}
+}
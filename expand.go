@@ -0,0 +1,81 @@
+package bsa
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// placeholderPattern matches a single bound placeholder in any of the
+// syntaxes rebindQuery produces.
+var placeholderPattern = regexp.MustCompile(`\?|\$\d+|@p\d+|:v\d+`)
+
+// expandSliceArgs expands each placeholder bound to a slice value (other
+// than []byte, which is passed through as a scalar BLOB) into one
+// placeholder per element, flattening the slice into values in its place and
+// renumbering the placeholders that follow it. This lets a query such as
+// "select * from users where id in (?)" be called with a []int64 without
+// the caller having to know its length up front, analogous to sqlx's In().
+//
+// Queries with no slice-valued args are returned unchanged.
+func expandSliceArgs(d Dialect, query string, values []any) (string, []any, error) {
+	matches := placeholderPattern.FindAllStringIndex(query, -1)
+	if len(matches) != len(values) {
+		// Argument count doesn't line up 1:1 with placeholders; leave the
+		// query as-is rather than guessing.
+		return query, values, nil
+	}
+
+	hasSlice := false
+	for _, v := range values {
+		if isExpandableSlice(v) {
+			hasSlice = true
+			break
+		}
+	}
+	if !hasSlice {
+		return query, values, nil
+	}
+
+	var b strings.Builder
+	expanded := make([]any, 0, len(values))
+	last, n := 0, 0
+
+	for i, m := range matches {
+		b.WriteString(query[last:m[0]])
+		v := values[i]
+		if isExpandableSlice(v) {
+			rv := reflect.ValueOf(v)
+			count := rv.Len()
+			if count == 0 {
+				return "", nil, fmt.Errorf("bsa: cannot expand empty slice argument at position %d", i+1)
+			}
+			parts := make([]string, count)
+			for j := 0; j < count; j++ {
+				n++
+				parts[j] = placeholderFor(d, n)
+				expanded = append(expanded, rv.Index(j).Interface())
+			}
+			b.WriteString(strings.Join(parts, ", "))
+		} else {
+			n++
+			b.WriteString(placeholderFor(d, n))
+			expanded = append(expanded, v)
+		}
+		last = m[1]
+	}
+	b.WriteString(query[last:])
+
+	return b.String(), expanded, nil
+}
+
+func isExpandableSlice(v any) bool {
+	if v == nil {
+		return false
+	}
+	if _, ok := v.([]byte); ok {
+		return false
+	}
+	return reflect.ValueOf(v).Kind() == reflect.Slice
+}
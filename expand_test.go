@@ -0,0 +1,62 @@
+package bsa
+
+import "testing"
+
+func TestExpandSliceArgsExpandsSliceIntoPlaceholders(t *testing.T) {
+	query, values, err := expandSliceArgs(DialectSQLite, "select * from users where id in (?) and active = ?", []any{[]int64{1, 2, 3}, true})
+	if err != nil {
+		t.Fatalf("expandSliceArgs: %v", err)
+	}
+	if query != "select * from users where id in (?, ?, ?) and active = ?" {
+		t.Fatalf("unexpected query: %s", query)
+	}
+	if len(values) != 4 || values[0] != int64(1) || values[1] != int64(2) || values[2] != int64(3) || values[3] != true {
+		t.Fatalf("unexpected values: %v", values)
+	}
+}
+
+func TestExpandSliceArgsRenumbersDialectPlaceholders(t *testing.T) {
+	query, values, err := expandSliceArgs(DialectPostgres, "select * from users where id in ($1) and age > $2", []any{[]int64{7, 8}, 18})
+	if err != nil {
+		t.Fatalf("expandSliceArgs: %v", err)
+	}
+	if query != "select * from users where id in ($1, $2) and age > $3" {
+		t.Fatalf("unexpected query: %s", query)
+	}
+	if len(values) != 3 || values[2] != 18 {
+		t.Fatalf("unexpected values: %v", values)
+	}
+}
+
+func TestExpandSliceArgsLeavesByteSlicesAlone(t *testing.T) {
+	query, values, err := expandSliceArgs(DialectSQLite, "select * from blobs where data = ?", []any{[]byte("raw")})
+	if err != nil {
+		t.Fatalf("expandSliceArgs: %v", err)
+	}
+	if query != "select * from blobs where data = ?" {
+		t.Fatalf("[]byte was expanded: %s", query)
+	}
+	if len(values) != 1 {
+		t.Fatalf("unexpected values: %v", values)
+	}
+}
+
+func TestExpandSliceArgsRejectsEmptySlice(t *testing.T) {
+	_, _, err := expandSliceArgs(DialectSQLite, "select * from users where id in (?)", []any{[]int64{}})
+	if err == nil {
+		t.Fatal("expected an error for an empty slice argument")
+	}
+}
+
+func TestExpandSliceArgsLeavesQueryUnchangedWithoutSlices(t *testing.T) {
+	query, values, err := expandSliceArgs(DialectSQLite, "select * from users where id = ?", []any{int64(1)})
+	if err != nil {
+		t.Fatalf("expandSliceArgs: %v", err)
+	}
+	if query != "select * from users where id = ?" {
+		t.Fatalf("query was rewritten unnecessarily: %s", query)
+	}
+	if len(values) != 1 || values[0] != int64(1) {
+		t.Fatalf("unexpected values: %v", values)
+	}
+}
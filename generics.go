@@ -0,0 +1,201 @@
+package bsa
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// rowScanner scans a single *sql.Rows row into a T by column name, resolved
+// once per reflect.Type instead of per call. It backs every @queryOne/@query
+// field the runner builds, regardless of whether T is a concrete struct or
+// the instantiation of a generic repository's type parameter -- reflection
+// doesn't care either way.
+type rowScanner struct {
+	fieldByColumn map[string]int
+}
+
+var scannerCache sync.Map // map[reflect.Type]*rowScanner
+
+func scannerFor(t reflect.Type) *rowScanner {
+	if cached, ok := scannerCache.Load(t); ok {
+		return cached.(*rowScanner)
+	}
+	s := &rowScanner{fieldByColumn: make(map[string]int, t.NumField())}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Tag.Get("db")
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		s.fieldByColumn[name] = i
+	}
+	actual, _ := scannerCache.LoadOrStore(t, s)
+	return actual.(*rowScanner)
+}
+
+func (s *rowScanner) scan(rows *sql.Rows, dst reflect.Value) error {
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	dest := make([]any, len(columns))
+	for i, column := range columns {
+		var discard any
+		if idx, ok := s.fieldByColumn[column]; ok {
+			dest[i] = dst.Field(idx).Addr().Interface()
+		} else {
+			dest[i] = &discard
+		}
+	}
+	return rows.Scan(dest...)
+}
+
+// scanOneInto scans the next row of rows into a freshly allocated, addressable
+// value of t, using the scanner cached for t. It returns sql.ErrNoRows if
+// rows has none, matching database/sql's own convention.
+func scanOneInto(rows *sql.Rows, t reflect.Type) (reflect.Value, error) {
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.Value{}, sql.ErrNoRows
+	}
+	ptr := reflect.New(t)
+	if err := scannerFor(t).scan(rows, ptr.Elem()); err != nil {
+		return reflect.Value{}, err
+	}
+	return ptr.Elem(), nil
+}
+
+// prepareQuery resolves query through qLoder's "file:" convention, rebinds
+// it for dialect, and -- mirroring resolveArgs' handling of a repository
+// field's single struct argument -- expands a lone struct arg into
+// paramNames order before letting expandSliceArgs turn any slice-valued arg
+// into an IN (...) list. It's the untagged-query counterpart of
+// compileFields/resolveArgs, reused by QueryOne, QueryAll and Exec.
+func prepareQuery(qLoder QueryLoader, dialect Dialect, query string, args []any) (string, []any, error) {
+	spec, err := loadQuerySpec(qLoder, query)
+	if err != nil {
+		return "", nil, err
+	}
+	script, paramNames := rebindQuery(dialect, spec)
+
+	values := args
+	if len(paramNames) > 0 && len(args) == 1 {
+		if v := reflect.ValueOf(args[0]); v.Kind() == reflect.Struct {
+			if values, err = structFieldValues(v, paramNames); err != nil {
+				return "", nil, err
+			}
+		}
+	}
+	return expandSliceArgs(dialect, script, values)
+}
+
+// QueryOne resolves, rebinds and runs query against handle, then scans the
+// single resulting row into a T using the scanner cached for T's
+// reflect.Type -- the same cached-scanner path an @queryOne-tagged field
+// uses, without needing a repository struct or a prior Bind call. It
+// returns sql.ErrNoRows if the query has no rows, matching database/sql's
+// own convention.
+//
+// QueryOne does not go through BindOptions.Middleware or Prepare; use a
+// tagged repository field via Bind/BindWithOptions for those.
+func QueryOne[T any](ctx context.Context, handle DBResource, qLoder QueryLoader, dialect Dialect, query string, args ...any) (T, error) {
+	var zero T
+	script, values, err := prepareQuery(qLoder, dialect, query, args)
+	if err != nil {
+		return zero, err
+	}
+
+	rows, err := handle.QueryContext(ctx, script, values...)
+	if err != nil {
+		return zero, err
+	}
+	defer rows.Close()
+
+	v, err := scanOneInto(rows, reflect.TypeOf(zero))
+	if err != nil {
+		return zero, err
+	}
+	return v.Interface().(T), nil
+}
+
+// QueryAll is QueryOne's every-row counterpart, scanning every row query
+// returns into a []T with the same cached scanner.
+func QueryAll[T any](ctx context.Context, handle DBResource, qLoder QueryLoader, dialect Dialect, query string, args ...any) ([]T, error) {
+	var zero T
+	script, values, err := prepareQuery(qLoder, dialect, query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := handle.QueryContext(ctx, script, values...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	slice, err := scanAllInto(rows, reflect.TypeOf(zero), false)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Interface().([]T), nil
+}
+
+// Exec resolves, rebinds and runs query against handle the same way an
+// @exec-tagged field does, without needing a repository struct or a prior
+// Bind call. lastInsertIDSupport mirrors Bind's parameter of the same name
+// and must be false for database systems (PostgreSQL) that don't support
+// the concept of last insert id.
+func Exec(ctx context.Context, handle DBResource, qLoder QueryLoader, dialect Dialect, lastInsertIDSupport bool, query string, args ...any) (lastInsertID int64, rowsAffected int64, err error) {
+	script, values, err := prepareQuery(qLoder, dialect, query, args)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	result, err := handle.ExecContext(ctx, script, values...)
+	if err != nil {
+		return 0, 0, err
+	}
+	if lastInsertIDSupport {
+		if lastInsertID, err = result.LastInsertId(); err != nil {
+			return 0, 0, err
+		}
+	}
+	if rowsAffected, err = result.RowsAffected(); err != nil {
+		return 0, 0, err
+	}
+	return lastInsertID, rowsAffected, nil
+}
+
+// scanAllInto scans every remaining row of rows into values of elemType,
+// sharing the same cached scanner as scanOneInto, and returns them as a
+// []elemType (or []*elemType, when pointerElems is set).
+func scanAllInto(rows *sql.Rows, elemType reflect.Type, pointerElems bool) (reflect.Value, error) {
+	scanner := scannerFor(elemType)
+
+	sliceType := reflect.SliceOf(elemType)
+	if pointerElems {
+		sliceType = reflect.SliceOf(reflect.PointerTo(elemType))
+	}
+	slice := reflect.MakeSlice(sliceType, 0, 0)
+
+	for rows.Next() {
+		ptr := reflect.New(elemType)
+		if err := scanner.scan(rows, ptr.Elem()); err != nil {
+			return reflect.Value{}, err
+		}
+		if pointerElems {
+			slice = reflect.Append(slice, ptr)
+		} else {
+			slice = reflect.Append(slice, ptr.Elem())
+		}
+	}
+	return slice, rows.Err()
+}
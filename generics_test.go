@@ -0,0 +1,63 @@
+package bsa_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/SharkFourSix/bsa"
+)
+
+// TestQueryOneAndQueryAll exercises the typed QueryOne/QueryAll entry points
+// against a raw, untagged query -- no repository struct or Bind call
+// involved.
+func TestQueryOneAndQueryAll(t *testing.T) {
+	db, err := createInMemoryDb()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	loader := diskFileQueryLoader()
+
+	if _, _, err := bsa.Exec(ctx, db, loader, bsa.DialectSQLite, true, "insert into users(name, age) values (?, ?)", "john", 65); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := bsa.Exec(ctx, db, loader, bsa.DialectSQLite, true, "insert into users(name, age) values (?, ?)", "jane", 30); err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := bsa.QueryOne[User](ctx, db, loader, bsa.DialectSQLite, "select * from users where name = ?", "john")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Name != "john" {
+		t.Fatalf("expected 'john', got %q", user.Name)
+	}
+
+	users, err := bsa.QueryAll[User](ctx, db, loader, bsa.DialectSQLite, "select * from users order by name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 {
+		t.Fatalf("expected 2 users, got %d", len(users))
+	}
+}
+
+// TestQueryOneNoRows checks that QueryOne reports sql.ErrNoRows, matching
+// database/sql's own convention. (A @queryOne-tagged field instead returns
+// the zero value and a nil error.)
+func TestQueryOneNoRows(t *testing.T) {
+	db, err := createInMemoryDb()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	_, err = bsa.QueryOne[User](context.Background(), db, diskFileQueryLoader(), bsa.DialectSQLite, "select * from users where id = ?", 404)
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected sql.ErrNoRows for a query with no rows, got %v", err)
+	}
+}
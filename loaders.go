@@ -83,3 +83,17 @@ func (l *fsQueryLoader) Get(name string) (string, error) {
 	}
 	return string(b), nil
 }
+
+// loadQuerySpec resolves a query spec as used in @exec/@query/@queryOne tags:
+// a literal query, or a "file:name" reference loaded through loader. The
+// ".sql" extension is appended to the referenced file name if missing.
+func loadQuerySpec(loader QueryLoader, spec string) (string, error) {
+	name, found := strings.CutPrefix(spec, "file:")
+	if !found {
+		return spec, nil
+	}
+	if !strings.HasSuffix(name, ".sql") {
+		name = name + ".sql"
+	}
+	return loader.Get(name)
+}
@@ -0,0 +1,97 @@
+package bsa
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// QueryKind identifies which kind of tagged function produced a QueryFunc
+// invocation.
+type QueryKind int
+
+const (
+	KindExec QueryKind = iota
+	KindQueryOne
+	KindQueryAll
+)
+
+func (k QueryKind) String() string {
+	switch k {
+	case KindExec:
+		return "exec"
+	case KindQueryOne:
+		return "queryOne"
+	case KindQueryAll:
+		return "queryAll"
+	default:
+		return "unknown"
+	}
+}
+
+// QueryResult is what a QueryFunc produces: Rows holds the scanned result
+// for KindQueryOne/KindQueryAll (nil for KindExec), while LastInsertID and
+// RowsAffected are populated for KindExec.
+type QueryResult struct {
+	Rows         any
+	LastInsertID int64
+	RowsAffected int64
+}
+
+// QueryFunc performs a single tagged query invocation.
+type QueryFunc func(ctx context.Context, kind QueryKind, query string, args []any) (QueryResult, error)
+
+// QueryMiddleware wraps a QueryFunc to add cross-cutting behavior (logging,
+// tracing, metrics, retries, ...) without modifying bsa itself. Middleware
+// declared in BindOptions.Middleware runs in the order given, outermost
+// first.
+type QueryMiddleware func(next QueryFunc) QueryFunc
+
+func chainMiddleware(mws []QueryMiddleware, terminal QueryFunc) QueryFunc {
+	chained := terminal
+	for i := len(mws) - 1; i >= 0; i-- {
+		chained = mws[i](chained)
+	}
+	return chained
+}
+
+// LoggingMiddleware logs every query's kind, text and duration to logger,
+// at error level on failure and debug level otherwise.
+func LoggingMiddleware(logger *slog.Logger) QueryMiddleware {
+	return func(next QueryFunc) QueryFunc {
+		return func(ctx context.Context, kind QueryKind, query string, args []any) (QueryResult, error) {
+			start := time.Now()
+			result, err := next(ctx, kind, query, args)
+			elapsed := time.Since(start)
+			if err != nil {
+				logger.ErrorContext(ctx, "bsa: query failed", "kind", kind.String(), "query", query, "duration", elapsed, "error", err)
+			} else {
+				logger.DebugContext(ctx, "bsa: query executed", "kind", kind.String(), "query", query, "duration", elapsed, "rowsAffected", result.RowsAffected)
+			}
+			return result, err
+		}
+	}
+}
+
+// OTelMiddleware starts a span named "bsa.<kind>" around every query,
+// recording the query text as a db.statement attribute and any error.
+func OTelMiddleware(tracer trace.Tracer) QueryMiddleware {
+	return func(next QueryFunc) QueryFunc {
+		return func(ctx context.Context, kind QueryKind, query string, args []any) (QueryResult, error) {
+			ctx, span := tracer.Start(ctx, "bsa."+kind.String())
+			defer span.End()
+			span.SetAttributes(attribute.String("db.statement", query))
+
+			result, err := next(ctx, kind, query, args)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			return result, err
+		}
+	}
+}
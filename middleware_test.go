@@ -0,0 +1,100 @@
+package bsa
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestChainMiddlewareRunsOutermostFirst(t *testing.T) {
+	var order []string
+
+	record := func(name string) QueryMiddleware {
+		return func(next QueryFunc) QueryFunc {
+			return func(ctx context.Context, kind QueryKind, query string, args []any) (QueryResult, error) {
+				order = append(order, name+":before")
+				result, err := next(ctx, kind, query, args)
+				order = append(order, name+":after")
+				return result, err
+			}
+		}
+	}
+
+	terminal := func(ctx context.Context, kind QueryKind, query string, args []any) (QueryResult, error) {
+		order = append(order, "terminal")
+		return QueryResult{RowsAffected: 1}, nil
+	}
+
+	result, err := chainMiddleware([]QueryMiddleware{record("outer"), record("inner")}, terminal)(context.Background(), KindExec, "select 1", nil)
+	if err != nil {
+		t.Fatalf("chainMiddleware: %v", err)
+	}
+	if result.RowsAffected != 1 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	want := []string{"outer:before", "inner:before", "terminal", "inner:after", "outer:after"}
+	if len(order) != len(want) {
+		t.Fatalf("unexpected call order: %v", order)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("unexpected call order: %v", order)
+		}
+	}
+}
+
+func TestChainMiddlewareWithNoMiddlewareRunsTerminalDirectly(t *testing.T) {
+	called := false
+	terminal := func(ctx context.Context, kind QueryKind, query string, args []any) (QueryResult, error) {
+		called = true
+		return QueryResult{}, nil
+	}
+
+	if _, err := chainMiddleware(nil, terminal)(context.Background(), KindQueryOne, "select 1", nil); err != nil {
+		t.Fatalf("chainMiddleware: %v", err)
+	}
+	if !called {
+		t.Fatal("terminal was never called")
+	}
+}
+
+func TestChainMiddlewarePropagatesError(t *testing.T) {
+	wantErr := errors.New("boom")
+	terminal := func(ctx context.Context, kind QueryKind, query string, args []any) (QueryResult, error) {
+		return QueryResult{}, wantErr
+	}
+
+	seenErr := false
+	observe := func(next QueryFunc) QueryFunc {
+		return func(ctx context.Context, kind QueryKind, query string, args []any) (QueryResult, error) {
+			result, err := next(ctx, kind, query, args)
+			if err != nil {
+				seenErr = true
+			}
+			return result, err
+		}
+	}
+
+	_, err := chainMiddleware([]QueryMiddleware{observe}, terminal)(context.Background(), KindExec, "select 1", nil)
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("expected wantErr, got %v", err)
+	}
+	if !seenErr {
+		t.Fatal("middleware never observed the terminal's error")
+	}
+}
+
+func TestQueryKindString(t *testing.T) {
+	cases := map[QueryKind]string{
+		KindExec:      "exec",
+		KindQueryOne:  "queryOne",
+		KindQueryAll:  "queryAll",
+		QueryKind(99): "unknown",
+	}
+	for kind, want := range cases {
+		if got := kind.String(); got != want {
+			t.Fatalf("QueryKind(%d).String() = %q, want %q", kind, got, want)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+// Package migrate implements a small schema migration runner on top of
+// bsa's QueryLoader/ResourceFS file-loading conventions. Migrations are
+// plain SQL files named "NNN_name.up.sql" / "NNN_name.down.sql" inside a
+// directory served by an fs.FS, discovered and applied version by version.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/SharkFourSix/bsa"
+)
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// migration is one versioned pair of up/down scripts.
+type migration struct {
+	version int64
+	name    string
+	up      string
+	down    string
+}
+
+// Up applies every pending migration found under res, in order.
+func Up(ctx context.Context, db *sql.DB, res *bsa.ResourceFS, dialect bsa.Dialect) error {
+	migrations, err := loadMigrations(res)
+	if err != nil {
+		return err
+	}
+	if len(migrations) == 0 {
+		return nil
+	}
+	return To(ctx, db, res, dialect, migrations[len(migrations)-1].version)
+}
+
+// Down rolls back every applied migration found under res, in reverse order.
+func Down(ctx context.Context, db *sql.DB, res *bsa.ResourceFS, dialect bsa.Dialect) error {
+	return To(ctx, db, res, dialect, 0)
+}
+
+// To migrates the schema to exactly the given version, applying pending "up"
+// scripts or reverting "down" scripts as needed.
+func To(ctx context.Context, db *sql.DB, res *bsa.ResourceFS, dialect bsa.Dialect, version int64) error {
+	if err := ensureSchemaTable(ctx, db, dialect); err != nil {
+		return fmt.Errorf("migrate: failed to create schema_migrations: %w", err)
+	}
+
+	migrations, err := loadMigrations(res)
+	if err != nil {
+		return err
+	}
+
+	return withLock(ctx, db, dialect, func(conn execQueryBeginner) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range migrations {
+			if m.version <= version && !applied[m.version] {
+				if err := applyOne(ctx, conn, dialect, m, m.up, true); err != nil {
+					return fmt.Errorf("migrate: up %d_%s: %w", m.version, m.name, err)
+				}
+			}
+		}
+		// Reverted in descending version order, the mirror image of the
+		// ascending pass above, so a down script can safely assume every
+		// migration after it has already been rolled back.
+		for i := len(migrations) - 1; i >= 0; i-- {
+			m := migrations[i]
+			if m.version > version && applied[m.version] {
+				if err := applyOne(ctx, conn, dialect, m, m.down, false); err != nil {
+					return fmt.Errorf("migrate: down %d_%s: %w", m.version, m.name, err)
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// loadMigrations scans res for "NNN_name.up.sql"/"NNN_name.down.sql" files,
+// loading their contents through the same QueryLoader path bsa.Bind uses,
+// and returns them sorted by version.
+func loadMigrations(res *bsa.ResourceFS) ([]migration, error) {
+	dir := res.RootPath
+	if dir == "" {
+		dir = "."
+	}
+
+	entries, err := fs.ReadDir(res.Source, dir)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: failed to read %s: %w", dir, err)
+	}
+
+	loader := bsa.NewResFSQueryLoader(res)
+	byVersion := map[int64]*migration{}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		m := fileNamePattern.FindStringSubmatch(entry.Name())
+		if m == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migrate: invalid version in %s: %w", entry.Name(), err)
+		}
+
+		script, err := loader.Get(entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("migrate: failed to load %s: %w", entry.Name(), err)
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &migration{version: version, name: m[2]}
+			byVersion[version] = mig
+		}
+		if m[3] == "up" {
+			mig.up = script
+		} else {
+			mig.down = script
+		}
+	}
+
+	migrations := make([]migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].version < migrations[j].version })
+	return migrations, nil
+}
+
+// execQueryBeginner is the subset of *sql.DB / *sql.Conn that the locked
+// section of To needs. Pinning callers to this interface (instead of
+// *sql.DB directly) lets withLock hand out a single *sql.Conn so the
+// advisory lock it takes actually covers every statement run while it's
+// held, regardless of how database/sql would otherwise spread pooled calls
+// across connections.
+type execQueryBeginner interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// withLock serializes concurrent migration runs using each dialect's
+// advisory lock where one is available. It pins a single *sql.Conn for the
+// duration of fn so the lock (which is scoped to the connection/session
+// that acquired it) actually covers the work fn does, then passes that same
+// conn to fn instead of the pooled *sql.DB. SQLite gets its exclusivity
+// from the per-migration serializable transaction in applyOne instead;
+// MSSQL and Oracle have no portable advisory lock exposed through
+// database/sql, so callers running those concurrently are responsible for
+// external locking.
+func withLock(ctx context.Context, db *sql.DB, dialect bsa.Dialect, fn func(conn execQueryBeginner) error) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	switch dialect {
+	case bsa.DialectPostgres:
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock(727274)"); err != nil {
+			return err
+		}
+		defer conn.ExecContext(ctx, "SELECT pg_advisory_unlock(727274)")
+	case bsa.DialectMySQL:
+		// GET_LOCK reports timeout/failure through its *return value* (0 =
+		// timed out, NULL = error due to e.g. a killed connection), not a
+		// Go error, so ExecContext alone would never notice either case.
+		var acquired sql.NullInt64
+		if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK('bsa_migrate', 10)").Scan(&acquired); err != nil {
+			return err
+		}
+		if !acquired.Valid || acquired.Int64 != 1 {
+			return fmt.Errorf("migrate: failed to acquire MySQL advisory lock 'bsa_migrate'")
+		}
+		defer conn.ExecContext(ctx, "SELECT RELEASE_LOCK('bsa_migrate')")
+	}
+	return fn(conn)
+}
+
+func applyOne(ctx context.Context, db execQueryBeginner, dialect bsa.Dialect, m migration, script string, up bool) error {
+	var opts *sql.TxOptions
+	if dialect == bsa.DialectSQLite {
+		// Approximates SQLite's "BEGIN EXCLUSIVE" so a concurrent migration
+		// run can't interleave with this one.
+		opts = &sql.TxOptions{Isolation: sql.LevelSerializable}
+	}
+
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range strings.Split(script, ";") {
+		stmt = strings.TrimSpace(stmt)
+		if stmt == "" {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+
+	if up {
+		if _, err := tx.ExecContext(ctx, insertDML(dialect), m.version, m.name); err != nil {
+			return err
+		}
+	} else {
+		if _, err := tx.ExecContext(ctx, deleteDML(dialect), m.version); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func ensureSchemaTable(ctx context.Context, db *sql.DB, dialect bsa.Dialect) error {
+	_, err := db.ExecContext(ctx, schemaTableDDL(dialect))
+	return err
+}
+
+func appliedVersions(ctx context.Context, db execQueryBeginner) (map[int64]bool, error) {
+	rows, err := db.QueryContext(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int64]bool{}
+	for rows.Next() {
+		var v int64
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		applied[v] = true
+	}
+	return applied, rows.Err()
+}
+
+func schemaTableDDL(dialect bsa.Dialect) string {
+	switch dialect {
+	case bsa.DialectPostgres:
+		return `CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT PRIMARY KEY, name TEXT NOT NULL, applied_at TIMESTAMP NOT NULL DEFAULT now())`
+	case bsa.DialectMySQL:
+		return `CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT PRIMARY KEY, name TEXT NOT NULL, applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP)`
+	case bsa.DialectMSSQL:
+		return `IF OBJECT_ID('schema_migrations', 'U') IS NULL CREATE TABLE schema_migrations (version BIGINT PRIMARY KEY, name NVARCHAR(255) NOT NULL, applied_at DATETIME2 NOT NULL DEFAULT SYSUTCDATETIME())`
+	case bsa.DialectOracle:
+		return `CREATE TABLE schema_migrations (version NUMBER PRIMARY KEY, name VARCHAR2(255) NOT NULL, applied_at TIMESTAMP DEFAULT SYSTIMESTAMP)`
+	default: // DialectSQLite
+		return `CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY, name TEXT NOT NULL, applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP)`
+	}
+}
+
+func insertDML(dialect bsa.Dialect) string {
+	switch dialect {
+	case bsa.DialectPostgres:
+		return "INSERT INTO schema_migrations (version, name) VALUES ($1, $2)"
+	case bsa.DialectMSSQL:
+		return "INSERT INTO schema_migrations (version, name) VALUES (@p1, @p2)"
+	case bsa.DialectOracle:
+		return "INSERT INTO schema_migrations (version, name) VALUES (:v1, :v2)"
+	default: // DialectMySQL, DialectSQLite
+		return "INSERT INTO schema_migrations (version, name) VALUES (?, ?)"
+	}
+}
+
+func deleteDML(dialect bsa.Dialect) string {
+	switch dialect {
+	case bsa.DialectPostgres:
+		return "DELETE FROM schema_migrations WHERE version = $1"
+	case bsa.DialectMSSQL:
+		return "DELETE FROM schema_migrations WHERE version = @p1"
+	case bsa.DialectOracle:
+		return "DELETE FROM schema_migrations WHERE version = :v1"
+	default: // DialectMySQL, DialectSQLite
+		return "DELETE FROM schema_migrations WHERE version = ?"
+	}
+}
@@ -0,0 +1,95 @@
+package migrate_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"testing/fstest"
+
+	"github.com/SharkFourSix/bsa"
+	"github.com/SharkFourSix/bsa/migrate"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("PRAGMA foreign_keys = ON"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// fixture declares a child table with a foreign key into the parent table
+// created by an earlier migration, so once a child row references a parent
+// row, rolling back in the wrong order (drop parent before child) fails with
+// a foreign key violation.
+func fixture() *bsa.ResourceFS {
+	return &bsa.ResourceFS{
+		Source: fstest.MapFS{
+			"migrations/001_parent.up.sql":   {Data: []byte(`CREATE TABLE parent(id INTEGER PRIMARY KEY)`)},
+			"migrations/001_parent.down.sql": {Data: []byte(`DROP TABLE parent`)},
+			"migrations/002_child.up.sql":    {Data: []byte(`CREATE TABLE child(id INTEGER PRIMARY KEY, parent_id INTEGER REFERENCES parent(id))`)},
+			"migrations/002_child.down.sql":  {Data: []byte(`DROP TABLE child`)},
+		},
+		RootPath: "migrations",
+	}
+}
+
+func TestUpThenDown(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	res := fixture()
+
+	if err := migrate.Up(ctx, db, res, bsa.DialectSQLite); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+
+	if _, err := db.Exec("INSERT INTO parent(id) VALUES (1)"); err != nil {
+		t.Fatalf("parent table not created: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO child(id, parent_id) VALUES (1, 1)"); err != nil {
+		t.Fatalf("child table not created: %v", err)
+	}
+
+	if err := migrate.Down(ctx, db, res, bsa.DialectSQLite); err != nil {
+		t.Fatalf("Down: %v", err)
+	}
+
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM schema_migrations").Scan(&count)
+	if err != nil {
+		t.Fatalf("schema_migrations query: %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("expected no applied migrations after Down, got %d", count)
+	}
+}
+
+func TestDownAppliesInReverseOrder(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	res := fixture()
+
+	if err := migrate.Up(ctx, db, res, bsa.DialectSQLite); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+
+	if _, err := db.Exec("INSERT INTO parent(id) VALUES (1)"); err != nil {
+		t.Fatalf("parent table not created: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO child(id, parent_id) VALUES (1, 1)"); err != nil {
+		t.Fatalf("child table not created: %v", err)
+	}
+
+	// Rolling back directly to 0 must drop child (002) before parent (001);
+	// doing it ascending would try to drop parent while child still
+	// references it and fail under PRAGMA foreign_keys = ON.
+	if err := migrate.To(ctx, db, res, bsa.DialectSQLite, 0); err != nil {
+		t.Fatalf("To(0): %v", err)
+	}
+}
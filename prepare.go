@@ -0,0 +1,41 @@
+package bsa
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+)
+
+// preparedStmts tracks the *sql.Stmt cache created for each dst passed to
+// BindWithOptions with BindOptions.Prepare set, so Close can find and
+// release them later.
+var preparedStmts sync.Map // map[any][]*sql.Stmt
+
+// statementFor returns stmt bound to executor: unchanged when executor is
+// the *sql.DB it was prepared against, or rebound to a transaction's
+// connection via Tx.StmtContext when executor is a *sql.Tx.
+func statementFor(ctx context.Context, stmt *sql.Stmt, executor DBResource) *sql.Stmt {
+	if tx, ok := executor.(*sql.Tx); ok {
+		return tx.StmtContext(ctx, stmt)
+	}
+	return stmt
+}
+
+// Close closes every prepared statement cached for dst by a prior
+// BindWithOptions call made with BindOptions.Prepare. It is a no-op if dst
+// was never bound with Prepare enabled.
+func Close(dst any) error {
+	cached, ok := preparedStmts.LoadAndDelete(dst)
+	if !ok {
+		return nil
+	}
+
+	var errs []error
+	for _, stmt := range cached.([]*sql.Stmt) {
+		if err := stmt.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,77 @@
+package bsa
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestStatementForRebindsToTx(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec("CREATE TABLE t(v INTEGER)"); err != nil {
+		t.Fatal(err)
+	}
+
+	stmt, err := db.Prepare("INSERT INTO t(v) VALUES (?)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	ctx := context.Background()
+
+	if got := statementFor(ctx, stmt, db); got != stmt {
+		t.Fatalf("statementFor(db) = %v, want the original stmt unchanged", got)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	txStmt := statementFor(ctx, stmt, tx)
+	if txStmt == stmt {
+		t.Fatal("statementFor(tx) returned the *sql.DB-bound statement unchanged")
+	}
+	if _, err := txStmt.ExecContext(ctx, 1); err != nil {
+		t.Fatalf("exec via tx-rebound statement: %v", err)
+	}
+}
+
+func TestCloseReleasesPreparedStatements(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	stmt, err := db.Prepare("SELECT 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := new(struct{})
+	preparedStmts.Store(key, []*sql.Stmt{stmt})
+
+	if err := Close(key); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if _, err := stmt.Query(); err == nil {
+		t.Fatal("expected querying a closed statement to fail")
+	}
+
+	// A second Close (or one for a dst that was never Prepare-bound) is a
+	// no-op, not an error.
+	if err := Close(key); err != nil {
+		t.Fatalf("Close on an already-closed dst: %v", err)
+	}
+}
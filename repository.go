@@ -0,0 +1,65 @@
+package bsa
+
+import (
+	"context"
+	"reflect"
+)
+
+// Repository wraps a struct of Bind-tagged functions together with the
+// metadata needed to rebind it against a different DBResource, so callers
+// don't have to thread a *sql.Tx through every call site by hand.
+type Repository[T any] struct {
+	Funcs T
+
+	ctx    context.Context
+	loader QueryLoader
+	opts   BindOptions
+	specs  []fieldSpec
+	ptr    *T
+}
+
+// NewRepository binds the tagged functions of a new T against handle and
+// wraps the result in a Repository.
+func NewRepository[T any](ctx context.Context, handle DBResource, loader QueryLoader, opts BindOptions) (*Repository[T], error) {
+	dst := new(T)
+
+	specs, err := compileFields(ctx, handle, reflect.TypeOf(*dst), loader, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := buildFuncs(ctx, handle, reflect.ValueOf(dst).Elem(), opts, specs); err != nil {
+		return nil, err
+	}
+
+	if stmts := stmtsOf(specs); len(stmts) > 0 {
+		preparedStmts.Store(dst, stmts)
+	}
+
+	return &Repository[T]{Funcs: *dst, ctx: ctx, loader: loader, opts: opts, specs: specs, ptr: dst}, nil
+}
+
+// WithTx returns a shallow clone of r whose bound functions run against res
+// (typically a *sql.Tx) instead of r's original handle. Unlike NewRepository,
+// it does not re-parse r's tagged queries, re-rebind their dialect
+// placeholders, or re-prepare them: it reuses r's already-compiled
+// []fieldSpec as-is and only rebuilds the thin reflect closures that
+// dispatch to res. If r was bound with BindOptions.Prepare set, the cached
+// *sql.Stmt values are reused too -- statementFor already rebinds each of
+// them to a *sql.Tx per call (see prepare.go), so nothing is re-prepared
+// against res, and res need not be a *sql.DB.
+func (r *Repository[T]) WithTx(res DBResource) (*Repository[T], error) {
+	dst := new(T)
+	if err := buildFuncs(r.ctx, res, reflect.ValueOf(dst).Elem(), r.opts, r.specs); err != nil {
+		return nil, err
+	}
+	return &Repository[T]{Funcs: *dst, ctx: r.ctx, loader: r.loader, opts: r.opts, specs: r.specs, ptr: r.ptr}, nil
+}
+
+// Close releases every statement cached for r by a prior Prepare-enabled
+// NewRepository call. It is a no-op if r was never bound with Prepare set.
+// A Repository returned by WithTx shares its statements with the Repository
+// it was derived from, so Close any one of them once, after every clone
+// derived from it is done.
+func (r *Repository[T]) Close() error {
+	return Close(r.ptr)
+}
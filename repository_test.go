@@ -0,0 +1,107 @@
+package bsa_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SharkFourSix/bsa"
+)
+
+func TestRepositoryWithTx(t *testing.T) {
+	db, err := createInMemoryDb()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	repo, err := bsa.NewRepository[UserRepository](ctx, db, diskFileQueryLoader(), bsa.BindOptions{
+		Dialect:             bsa.DialectSQLite,
+		LastInsertIDSupport: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	txRepo, err := repo.WithTx(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lastInsertID, _, err := txRepo.Funcs.AddUser("tx-john", 40)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Visible inside the transaction...
+	if _, err := txRepo.Funcs.SelectUser(lastInsertID); err != nil {
+		t.Fatalf("row not visible inside tx: %v", err)
+	}
+
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+
+	// ...but gone once it's rolled back, via the original (non-tx) repo.
+	user, err := repo.Funcs.SelectUser(lastInsertID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user != nil {
+		t.Fatalf("row survived rollback: %+v", user)
+	}
+}
+
+// TestRepositoryWithTxAndPrepare exercises BindOptions.Prepare together
+// with WithTx and Close: WithTx must succeed against a *sql.Tx (Prepare
+// otherwise requires a *sql.DB), the tx-bound functions must still use the
+// statements prepared against db, and Close must release them.
+func TestRepositoryWithTxAndPrepare(t *testing.T) {
+	db, err := createInMemoryDb()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	repo, err := bsa.NewRepository[UserRepository](ctx, db, diskFileQueryLoader(), bsa.BindOptions{
+		Dialect:             bsa.DialectSQLite,
+		LastInsertIDSupport: true,
+		Prepare:             true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txRepo, err := repo.WithTx(tx)
+	if err != nil {
+		t.Fatalf("WithTx against a *sql.Tx with Prepare set: %v", err)
+	}
+
+	lastInsertID, _, err := txRepo.Funcs.AddUser("prepared-jane", 22)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	if user, err := repo.Funcs.SelectUser(lastInsertID); err != nil || user.Name != "prepared-jane" {
+		t.Fatalf("committed row not found via repo: user=%v err=%v", user, err)
+	}
+
+	if err := repo.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+package bsa
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Tx begins a transaction on db, invokes fn with the transaction bound as a
+// DBResource, and commits when fn returns nil. If fn returns an error or
+// panics, the transaction is rolled back (a panic is re-raised after the
+// rollback so callers still see it).
+func Tx(ctx context.Context, db *sql.DB, fn func(res DBResource) error) error {
+	return TxWithOptions(ctx, db, nil, fn)
+}
+
+// TxWithOptions is the *sql.TxOptions-aware counterpart of Tx.
+func TxWithOptions(ctx context.Context, db *sql.DB, opts *sql.TxOptions, fn func(res DBResource) error) (err error) {
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	err = fn(tx)
+	return err
+}